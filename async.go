@@ -0,0 +1,113 @@
+package rtltcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ReadAsync mirrors librtlsdr's rtlsdr_read_async: it reads numBufs buffers
+// of bufLen bytes (or until ctx is cancelled if numBufs <= 0) and invokes cb
+// with each one in turn. Buffers are drawn from an internal pool shared with
+// Samples so repeated calls avoid allocating on every read; cb must not
+// retain the slice it is given since it is returned to the pool as soon as
+// cb returns. ReadAsync blocks until ctx is done, numBufs buffers have been
+// delivered, or a read fails.
+func (sdr *SDR) ReadAsync(ctx context.Context, bufLen, numBufs int, cb func([]byte)) error {
+	sdr.initPool(bufLen)
+
+	stop := make(chan struct{})
+	watcherDone := make(chan struct{})
+	go func() {
+		defer close(watcherDone)
+		select {
+		case <-ctx.Done():
+			sdr.SetReadDeadline(time.Now())
+		case <-stop:
+		}
+	}()
+	defer func() {
+		close(stop)
+		<-watcherDone
+		// Clear any deadline the watcher set so a later ReadAsync/Samples
+		// call on the same SDR isn't handed an already-expired deadline.
+		sdr.SetReadDeadline(time.Time{})
+	}()
+
+	for i := 0; numBufs <= 0 || i < numBufs; i++ {
+		buf := sdr.bufPool.Get().([]byte)
+
+		_, err := io.ReadFull(sdr, buf)
+		if err != nil {
+			sdr.bufPool.Put(buf)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("error reading samples: %w", err)
+		}
+
+		cb(buf)
+		sdr.bufPool.Put(buf)
+	}
+
+	return nil
+}
+
+// Samples is the channel-based counterpart to ReadAsync. It launches a
+// background goroutine that reads numBufs buffers of bufLen bytes (or until
+// ctx is cancelled if numBufs <= 0) and sends each one on the returned
+// channel, which is closed once the read loop stops. Unlike ReadAsync, the
+// receiver owns the buffer it is sent until it calls ReleaseBuffer, so it is
+// safe to hand a sample off to another goroutine before releasing it.
+func (sdr *SDR) Samples(ctx context.Context, bufLen, numBufs int) <-chan []byte {
+	sdr.initPool(bufLen)
+	out := make(chan []byte)
+
+	go func() {
+		defer close(out)
+
+		sdr.ReadAsync(ctx, bufLen, numBufs, func(buf []byte) {
+			cp := sdr.bufPool.Get().([]byte)
+			copy(cp, buf)
+
+			select {
+			case out <- cp:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	return out
+}
+
+// ReleaseBuffer returns buf to the pool backing ReadAsync and Samples so it
+// can be recycled by a future read. Only buffers received from Samples need
+// to be released; ReadAsync recycles its buffer automatically once cb
+// returns.
+func (sdr *SDR) ReleaseBuffer(buf []byte) {
+	if sdr.bufPool == nil {
+		return
+	}
+	sdr.bufPool.Put(buf)
+}
+
+// initPool creates sdr's buffer pool on first use, sized to bufLen. A
+// single *SDR can only ever back one buffer size: calling ReadAsync or
+// Samples again with a different bufLen is a programmer error and panics,
+// rather than silently handing out buffers of the wrong length.
+func (sdr *SDR) initPool(bufLen int) {
+	if sdr.bufPool != nil {
+		if sdr.poolBufLen != bufLen {
+			panic(fmt.Sprintf("rtltcp: bufLen changed from %d to %d on the same SDR", sdr.poolBufLen, bufLen))
+		}
+		return
+	}
+	sdr.poolBufLen = bufLen
+	sdr.bufPool = &sync.Pool{
+		New: func() interface{} {
+			return make([]byte, bufLen)
+		},
+	}
+}
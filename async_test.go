@@ -0,0 +1,97 @@
+package rtltcp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAsync(t *testing.T) {
+	var sdr SDR
+	var remote net.Conn
+	remote, sdr.Conn = net.Pipe()
+	defer remote.Close()
+
+	go func() {
+		remote.Write(bytes16())
+		remote.Write(bytes16())
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got int
+	err := sdr.ReadAsync(ctx, 16, 2, func(buf []byte) {
+		got++
+		assert.Len(t, buf, 16)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, got)
+}
+
+func TestSamplesCancel(t *testing.T) {
+	var sdr SDR
+	var remote net.Conn
+	remote, sdr.Conn = net.Pipe()
+	defer remote.Close()
+
+	go func() {
+		for {
+			if _, err := remote.Write(bytes16()); err != nil {
+				return
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	n := 0
+	for buf := range sdr.Samples(ctx, 16, 0) {
+		n++
+		sdr.ReleaseBuffer(buf)
+	}
+	assert.Greater(t, n, 0)
+}
+
+func TestReadAsyncResetsDeadlineAfterCancel(t *testing.T) {
+	var sdr SDR
+	var remote net.Conn
+	remote, sdr.Conn = net.Pipe()
+	defer remote.Close()
+
+	go func() {
+		for {
+			if _, err := remote.Write(bytes16()); err != nil {
+				return
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := sdr.ReadAsync(ctx, 16, 0, func(buf []byte) {})
+	assert.NotNil(t, err)
+
+	// A later call on the same SDR must not see the deadline the cancelled
+	// call left behind.
+	err = sdr.ReadAsync(context.Background(), 16, 1, func(buf []byte) {})
+	assert.Nil(t, err)
+}
+
+func TestInitPoolPanicsOnBufLenChange(t *testing.T) {
+	var sdr SDR
+	sdr.initPool(16)
+
+	assert.Panics(t, func() {
+		sdr.initPool(32)
+	})
+}
+
+func bytes16() []byte {
+	return make([]byte, 16)
+}
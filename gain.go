@@ -0,0 +1,79 @@
+package rtltcp
+
+import "fmt"
+
+// gainTables holds the hard-coded supported-gain lists from librtlsdr, in dB
+// (matching Config.TunerGain's units), keyed by Tuner.
+var gainTables = map[Tuner][]float64{
+	// E4000: e4k_gains
+	1: {-1.0, 1.5, 4.0, 6.5, 9.0, 11.5, 14.0, 16.5, 19.0, 21.5, 24.0, 29.0, 34.0, 42.0},
+	// FC0012: fc0012_gains
+	2: {-9.9, -4.0, 7.1, 17.9, 19.2},
+	// FC0013: fc0013_gains
+	3: {-9.9, -7.3, -6.5, -6.3, -6.0, -5.8, -5.4, 5.8, 6.1, 6.3, 6.5, 6.7, 6.8, 7.0, 7.1, 17.9, 18.1, 18.2, 18.4, 18.6, 18.8, 19.1, 19.7},
+	// FC2580 has no discrete gain table; gain is AGC controlled.
+	4: {0.0},
+	// R820T
+	5: {0.0, 0.9, 1.4, 2.7, 3.7, 7.7, 8.7, 12.5, 14.4, 15.7, 16.6, 19.7, 20.7, 22.9, 25.4, 28.0, 29.7, 32.8, 33.8, 36.4, 37.2, 38.6, 40.2, 42.1, 43.4, 43.9, 44.5, 48.0, 49.6},
+	// R828D
+	6: {0.0, 0.9, 1.4, 2.7, 3.7, 7.7, 8.7, 12.5, 14.4, 15.7, 16.6, 19.7, 20.7, 22.9, 25.4, 28.0, 29.7, 32.8, 33.8, 36.4, 37.2, 38.6, 40.2, 42.1, 43.4, 43.9, 44.5, 48.0, 49.6},
+}
+
+// SupportedGains returns the gain-in-dB table librtlsdr uses for the
+// detected Tuner, in the same order as the index expected by
+// SetGainByIndex. It returns nil for an unrecognized tuner.
+func (sdr SDR) SupportedGains() []float64 {
+	return gainTables[sdr.Info.Tuner]
+}
+
+// NearestGain snaps db to the closest entry in SupportedGains and returns
+// its index (usable with SetGainByIndex) along with the actual gain value
+// at that index. If the Tuner has no gain table, it returns 0, 0.
+func (sdr SDR) NearestGain(db float64) (idx uint32, actual float64) {
+	gains := sdr.SupportedGains()
+	if len(gains) == 0 {
+		return 0, 0
+	}
+
+	best := 0
+	bestDiff := absFloat(gains[0] - db)
+	for i, g := range gains[1:] {
+		if diff := absFloat(g - db); diff < bestDiff {
+			best, bestDiff = i+1, diff
+		}
+	}
+
+	return uint32(best), gains[best]
+}
+
+// validateTunerGain checks that Config.TunerGain falls within the detected
+// Tuner's SupportedGains range, returning an error if it doesn't. Tuners
+// with no known gain table are not validated.
+func (sdr SDR) validateTunerGain() error {
+	gains := sdr.SupportedGains()
+	if len(gains) == 0 {
+		return nil
+	}
+
+	lo, hi := gains[0], gains[0]
+	for _, g := range gains[1:] {
+		if g < lo {
+			lo = g
+		}
+		if g > hi {
+			hi = g
+		}
+	}
+
+	if sdr.Config.TunerGain < lo || sdr.Config.TunerGain > hi {
+		return fmt.Errorf("tuner gain %.1fdB out of range [%.1f, %.1f] for %s", sdr.Config.TunerGain, lo, hi, sdr.Info.Tuner)
+	}
+	return nil
+}
+
+func absFloat(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
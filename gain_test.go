@@ -0,0 +1,61 @@
+package rtltcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupportedGains(t *testing.T) {
+	sdr := SDR{Info: DongleInfo{Tuner: 5}}
+	gains := sdr.SupportedGains()
+	assert.Len(t, gains, 29)
+	assert.Equal(t, 0.0, gains[0])
+	assert.Equal(t, 49.6, gains[len(gains)-1])
+
+	unknown := SDR{Info: DongleInfo{Tuner: 99}}
+	assert.Nil(t, unknown.SupportedGains())
+}
+
+func TestGainTables(t *testing.T) {
+	testCases := []struct {
+		tuner    Tuner
+		expected []float64
+	}{
+		{1, []float64{-1.0, 1.5, 4.0, 6.5, 9.0, 11.5, 14.0, 16.5, 19.0, 21.5, 24.0, 29.0, 34.0, 42.0}},                                                   // E4000
+		{2, []float64{-9.9, -4.0, 7.1, 17.9, 19.2}},                                                                                                      // FC0012
+		{3, []float64{-9.9, -7.3, -6.5, -6.3, -6.0, -5.8, -5.4, 5.8, 6.1, 6.3, 6.5, 6.7, 6.8, 7.0, 7.1, 17.9, 18.1, 18.2, 18.4, 18.6, 18.8, 19.1, 19.7}}, // FC0013
+		{4, []float64{0.0}}, // FC2580
+		{5, []float64{0.0, 0.9, 1.4, 2.7, 3.7, 7.7, 8.7, 12.5, 14.4, 15.7, 16.6, 19.7, 20.7, 22.9, 25.4, 28.0, 29.7, 32.8, 33.8, 36.4, 37.2, 38.6, 40.2, 42.1, 43.4, 43.9, 44.5, 48.0, 49.6}}, // R820T
+		{6, []float64{0.0, 0.9, 1.4, 2.7, 3.7, 7.7, 8.7, 12.5, 14.4, 15.7, 16.6, 19.7, 20.7, 22.9, 25.4, 28.0, 29.7, 32.8, 33.8, 36.4, 37.2, 38.6, 40.2, 42.1, 43.4, 43.9, 44.5, 48.0, 49.6}}, // R828D
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.tuner.String(), func(t *testing.T) {
+			sdr := SDR{Info: DongleInfo{Tuner: tc.tuner}}
+			assert.Equal(t, tc.expected, sdr.SupportedGains())
+		})
+	}
+}
+
+func TestNearestGain(t *testing.T) {
+	sdr := SDR{Info: DongleInfo{Tuner: 5}}
+
+	idx, actual := sdr.NearestGain(8.5)
+	assert.Equal(t, uint32(6), idx)
+	assert.Equal(t, 8.7, actual)
+
+	idx, actual = sdr.NearestGain(-100)
+	assert.Equal(t, uint32(0), idx)
+	assert.Equal(t, 0.0, actual)
+}
+
+func TestValidateTunerGain(t *testing.T) {
+	sdr := SDR{Info: DongleInfo{Tuner: 5}}
+
+	sdr.Config.TunerGain = 20.7
+	assert.Nil(t, sdr.validateTunerGain())
+
+	sdr.Config.TunerGain = 100
+	assert.NotNil(t, sdr.validateTunerGain())
+}
@@ -0,0 +1,312 @@
+package rtltcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LocalServerOpts configures StartLocalServer.
+type LocalServerOpts struct {
+	DeviceIndex int    // -d flag: rtl-sdr device index to bind
+	BindAddr    string // host goes to -a, port goes to -p
+	PPM         int    // -P flag: frequency correction in ppm
+	Binary      string // path to the rtl_tcp binary; defaults to "rtl_tcp"
+
+	// Logger receives the child process's stdout/stderr lines, one Print
+	// call per line. Defaults to log.Default().
+	Logger *log.Logger
+}
+
+// listeningRE matches rtl_tcp's "Listening on ...:PORT" startup line so
+// LocalServer can discover the port it actually bound, even when BindAddr
+// asked for port 0.
+var listeningRE = regexp.MustCompile(`[Ll]istening on .*:(\d+)`)
+
+// LocalServer supervises a local rtl_tcp subprocess, restarting it with
+// backoff if it exits unexpectedly, and hands out SDR connections to it via
+// Dial. Construct one with StartLocalServer.
+type LocalServer struct {
+	opts LocalServerOpts
+	log  *log.Logger
+
+	mu       sync.Mutex
+	addr     string
+	addrErr  error
+	addrOnce chan struct{}
+
+	listener net.Listener // set only for StartEmbeddedServer
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartLocalServer launches opts.Binary (or "rtl_tcp" if unset) as a
+// supervised subprocess and returns once its "listening on" line has been
+// observed on stdout/stderr, or the process fails to start. The supervisor
+// keeps running in the background, restarting the child with exponential
+// backoff if it crashes, until Close is called.
+func StartLocalServer(opts LocalServerOpts) (*LocalServer, error) {
+	if opts.Binary == "" {
+		opts.Binary = "rtl_tcp"
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.Default()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &LocalServer{
+		opts:     opts,
+		log:      opts.Logger,
+		addrOnce: make(chan struct{}),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	go s.supervise(ctx)
+
+	select {
+	case <-s.addrOnce:
+	case <-time.After(10 * time.Second):
+		s.Close()
+		return nil, fmt.Errorf("timed out waiting for %s to start listening", opts.Binary)
+	}
+
+	s.mu.Lock()
+	err := s.addrErr
+	s.mu.Unlock()
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Dial connects to the server's listen address. It does not wait for the
+// address to be discovered: if none is known yet it returns an error at once.
+// ctx only bounds the dial timeout, which is 5s when ctx has no deadline.
+func (s *LocalServer) Dial(ctx context.Context) (*SDR, error) {
+	s.mu.Lock()
+	addr := s.addr
+	s.mu.Unlock()
+
+	if addr == "" {
+		return nil, fmt.Errorf("rtltcp: local server has no listen address yet")
+	}
+
+	sdr := new(SDR)
+	deadline, ok := ctx.Deadline()
+	timeout := 5 * time.Second
+	if ok {
+		timeout = time.Until(deadline)
+	}
+	if err := sdr.Connect(addr, timeout); err != nil {
+		return nil, err
+	}
+	return sdr, nil
+}
+
+// Close stops the supervisor and its child process (or embedded listener),
+// waiting for it to exit before returning.
+func (s *LocalServer) Close() error {
+	s.cancel()
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	<-s.done
+	return nil
+}
+
+// StartEmbeddedServer runs a Go-native stand-in for rtl_tcp on bindAddr: it
+// serves each accepted connection in its own goroutine, writing info as the
+// dongle header and then copying bytes from source until the connection or
+// source closes. Concurrent connections all read from the same source.
+// Configuration commands sent by the client are read and discarded. This is
+// meant for tests that need an *SDR without a real dongle or rtl_tcp binary.
+func StartEmbeddedServer(bindAddr string, info DongleInfo, source io.Reader) (*LocalServer, error) {
+	ln, err := net.Listen("tcp", bindAddr)
+	if err != nil {
+		return nil, fmt.Errorf("error starting embedded server: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &LocalServer{
+		log:      log.Default(),
+		addr:     ln.Addr().String(),
+		addrOnce: make(chan struct{}),
+		listener: ln,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	close(s.addrOnce)
+
+	go s.acceptEmbedded(ctx, ln, info, source)
+
+	return s, nil
+}
+
+// acceptEmbedded serves connections on ln until ctx is done or ln is closed.
+func (s *LocalServer) acceptEmbedded(ctx context.Context, ln net.Listener, info DongleInfo, source io.Reader) {
+	defer close(s.done)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+			if err := binary.Write(conn, binary.BigEndian, info); err != nil {
+				return
+			}
+			go io.Copy(io.Discard, conn) // discard configuration commands
+			io.Copy(conn, source)
+		}()
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// supervise runs opts.Binary, restarting it with exponential backoff
+// whenever it exits, until ctx is done.
+func (s *LocalServer) supervise(ctx context.Context) {
+	defer close(s.done)
+
+	const (
+		backoffInitial = 500 * time.Millisecond
+		backoffMax     = 30 * time.Second
+	)
+	backoff := backoffInitial
+
+	for {
+		start := time.Now()
+		err := s.runOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.log.Printf("rtltcp: %s exited: %v", s.opts.Binary, err)
+
+		if time.Since(start) > backoffMax {
+			backoff = backoffInitial
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff *= 2
+		if backoff > backoffMax {
+			backoff = backoffMax
+		}
+	}
+}
+
+// runOnce starts opts.Binary once, streams its stdout/stderr to opts.Logger
+// (also watching for the "listening on" line), and blocks until it exits or
+// ctx is cancelled.
+func (s *LocalServer) runOnce(ctx context.Context) error {
+	args, err := rtlTCPArgs(s.opts)
+	if err != nil {
+		s.reportAddr("", err)
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, s.opts.Binary, args...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("error attaching stdout: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("error attaching stderr: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		s.reportAddr("", fmt.Errorf("error starting %s: %w", s.opts.Binary, err))
+		return err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go s.scanOutput(&wg, stdout)
+	go s.scanOutput(&wg, stderr)
+	wg.Wait()
+
+	return cmd.Wait()
+}
+
+// rtlTCPArgs builds the rtl_tcp command-line arguments for opts: BindAddr's
+// host goes to -a and its port to -p (rtl_tcp's -p is the listen port, not
+// ppm), -d selects the device index, and PPM, if set, goes to -P.
+func rtlTCPArgs(opts LocalServerOpts) ([]string, error) {
+	host, port, err := net.SplitHostPort(opts.BindAddr)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing BindAddr %q: %w", opts.BindAddr, err)
+	}
+
+	args := []string{
+		"-a", host,
+		"-p", port,
+		"-d", strconv.Itoa(opts.DeviceIndex),
+	}
+	if opts.PPM != 0 {
+		args = append(args, "-P", strconv.Itoa(opts.PPM))
+	}
+	return args, nil
+}
+
+// dialHost returns the host Dial should use to reach the subprocess: the
+// host half of opts.BindAddr, or "127.0.0.1" if that host is empty or the
+// wildcard "0.0.0.0" (rtl_tcp reports its listening port but not which
+// interface address a caller should actually dial).
+func (s *LocalServer) dialHost() string {
+	host, _, err := net.SplitHostPort(s.opts.BindAddr)
+	if err != nil || host == "" || host == "0.0.0.0" {
+		return "127.0.0.1"
+	}
+	return host
+}
+
+// scanOutput logs each line from r and, the first time this LocalServer
+// sees a "listening on" line, records the address it reports.
+func (s *LocalServer) scanOutput(wg *sync.WaitGroup, r io.Reader) {
+	defer wg.Done()
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		s.log.Print(line)
+
+		if m := listeningRE.FindStringSubmatch(line); m != nil {
+			s.reportAddr(net.JoinHostPort(s.dialHost(), m[1]), nil)
+		}
+	}
+}
+
+// reportAddr records the server's discovered address (or startup error) and
+// signals addrOnce the first time it's called.
+func (s *LocalServer) reportAddr(addr string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.addr != "" || s.addrErr != nil {
+		return
+	}
+	s.addr, s.addrErr = addr, err
+	close(s.addrOnce)
+}
@@ -0,0 +1,66 @@
+package rtltcp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRtlTCPArgs(t *testing.T) {
+	args, err := rtlTCPArgs(LocalServerOpts{DeviceIndex: 1, BindAddr: "127.0.0.1:1234", PPM: 5})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, []string{"-a", "127.0.0.1", "-p", "1234", "-d", "1", "-P", "5"}, args)
+
+	args, err = rtlTCPArgs(LocalServerOpts{BindAddr: "127.0.0.1:0"})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, []string{"-a", "127.0.0.1", "-p", "0", "-d", "0"}, args)
+
+	_, err = rtlTCPArgs(LocalServerOpts{BindAddr: "not-a-host-port"})
+	assert.NotNil(t, err)
+}
+
+func TestDialHost(t *testing.T) {
+	s := &LocalServer{opts: LocalServerOpts{BindAddr: "192.168.1.5:1234"}}
+	assert.Equal(t, "192.168.1.5", s.dialHost())
+
+	s = &LocalServer{opts: LocalServerOpts{BindAddr: "0.0.0.0:1234"}}
+	assert.Equal(t, "127.0.0.1", s.dialHost())
+
+	s = &LocalServer{opts: LocalServerOpts{BindAddr: ":1234"}}
+	assert.Equal(t, "127.0.0.1", s.dialHost())
+}
+
+func TestStartEmbeddedServer(t *testing.T) {
+	source := bytes.NewReader(make([]byte, 32))
+	info := DongleInfo{Magic: dongleMagic, Tuner: 5, GainCount: 29}
+
+	srv, err := StartEmbeddedServer("127.0.0.1:0", info, source)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	sdr, err := srv.Dial(ctx)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer sdr.Close()
+
+	assert.True(t, sdr.Info.Valid())
+	assert.Equal(t, Tuner(5), sdr.Info.Tuner)
+
+	buf := make([]byte, 32)
+	_, err = io.ReadFull(sdr, buf)
+	assert.Nil(t, err)
+}
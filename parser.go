@@ -0,0 +1,165 @@
+package rtltcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// MessageKind identifies the payload carried by a Message.
+type MessageKind int
+
+const (
+	// MessageSamples carries a Parser's decoded output in Data.
+	MessageSamples MessageKind = iota
+	// MessageError carries a terminal Configure or read error in Err; Pipe
+	// closes its channel after sending one.
+	MessageError
+)
+
+// Message is what a Parser emits from a chunk of samples, or what Pipe
+// emits itself when the underlying read fails.
+type Message struct {
+	Kind MessageKind
+	Data interface{}
+	Err  error
+}
+
+// Parser decodes raw IQ samples read from an SDR into zero or more
+// Messages, in the spirit of rtlamr's scm/idm/r900 dispatch. Implementations
+// should not retain the samples slice past the call, as Pipe reuses its
+// buffer between calls.
+type Parser interface {
+	// Parse decodes one chunk of samples, of length ChunkSize(), into zero
+	// or more Messages.
+	Parse(samples []byte) []Message
+
+	// PreferredConfig is the Config Pipe applies via SDR.Configure if the
+	// caller hasn't already set one.
+	PreferredConfig() Config
+
+	// ChunkSize is the number of bytes Pipe should read before each call
+	// to Parse.
+	ChunkSize() int
+}
+
+// Pipe reads samples from sdr in Parser-sized chunks and runs them through
+// p, sending every resulting Message on the returned channel. If sdr.Config
+// is still its zero value, Pipe configures sdr with p.PreferredConfig()
+// first. Pipe blocks the read loop while the channel's consumer is slow,
+// and closes the channel after ctx is done or Configure or a read fails,
+// sending a MessageError in the latter two cases.
+func (sdr *SDR) Pipe(ctx context.Context, p Parser) <-chan Message {
+	out := make(chan Message)
+
+	go func() {
+		defer close(out)
+
+		if sdr.Config == (Config{}) {
+			sdr.Config = p.PreferredConfig()
+		}
+		if err := sdr.Configure(); err != nil {
+			sendMessage(ctx, out, Message{Kind: MessageError, Err: err})
+			return
+		}
+
+		buf := make([]byte, p.ChunkSize())
+		for {
+			if _, err := io.ReadFull(sdr, buf); err != nil {
+				sendMessage(ctx, out, Message{Kind: MessageError, Err: fmt.Errorf("error reading samples: %w", err)})
+				return
+			}
+
+			for _, msg := range p.Parse(buf) {
+				if !sendMessage(ctx, out, msg) {
+					return
+				}
+			}
+
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// sendMessage sends msg on out, returning false without sending if ctx is
+// done first.
+func sendMessage(ctx context.Context, out chan<- Message, msg Message) bool {
+	select {
+	case out <- msg:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// NullParser discards every chunk it's given; it exists as the simplest
+// possible Parser for exercising Pipe in tests.
+type NullParser struct {
+	Config Config
+	Chunk  int
+}
+
+// Parse implements Parser by returning no Messages.
+func (NullParser) Parse(samples []byte) []Message { return nil }
+
+// PreferredConfig implements Parser.
+func (p NullParser) PreferredConfig() Config {
+	if p.Config == (Config{}) {
+		return DefaultConfig()
+	}
+	return p.Config
+}
+
+// ChunkSize implements Parser.
+func (p NullParser) ChunkSize() int {
+	if p.Chunk == 0 {
+		return 16384
+	}
+	return p.Chunk
+}
+
+// IQSample is one complex sample decoded by IQToFloat32Parser, with I and Q
+// each in [-1, 1].
+type IQSample struct {
+	I, Q float32
+}
+
+// IQToFloat32Parser converts raw unsigned-8-bit interleaved I/Q samples
+// into IQSample values, one MessageSamples per chunk carrying []IQSample as
+// Data.
+type IQToFloat32Parser struct {
+	Config Config
+	Chunk  int
+}
+
+// Parse implements Parser by decoding samples into a single MessageSamples.
+func (p IQToFloat32Parser) Parse(samples []byte) []Message {
+	out := make([]IQSample, len(samples)/2)
+	for i := range out {
+		out[i] = IQSample{
+			I: (float32(samples[2*i]) - 127.5) / 127.5,
+			Q: (float32(samples[2*i+1]) - 127.5) / 127.5,
+		}
+	}
+	return []Message{{Kind: MessageSamples, Data: out}}
+}
+
+// PreferredConfig implements Parser.
+func (p IQToFloat32Parser) PreferredConfig() Config {
+	if p.Config == (Config{}) {
+		return DefaultConfig()
+	}
+	return p.Config
+}
+
+// ChunkSize implements Parser.
+func (p IQToFloat32Parser) ChunkSize() int {
+	if p.Chunk == 0 {
+		return 16384
+	}
+	return p.Chunk
+}
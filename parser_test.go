@@ -0,0 +1,60 @@
+package rtltcp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeIQToFloat32(t *testing.T) {
+	var sdr SDR
+	var remote net.Conn
+	remote, sdr.Conn = net.Pipe()
+	defer remote.Close()
+
+	go remote.Write([]byte{0, 255, 127, 128})
+	go discardCommands(remote)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	msg := <-sdr.Pipe(ctx, IQToFloat32Parser{Chunk: 4})
+	if !assert.Equal(t, MessageSamples, msg.Kind) {
+		t.FailNow()
+	}
+
+	samples := msg.Data.([]IQSample)
+	assert.Len(t, samples, 2)
+	assert.InDelta(t, -1.0, samples[0].I, 1e-6)
+	assert.InDelta(t, 1.0, samples[0].Q, 1e-6)
+}
+
+// With the remote end already closed, Configure's first command write fails,
+// so the only Message Pipe sends is a MessageError.
+func TestPipeConfigureError(t *testing.T) {
+	var sdr SDR
+	var remote net.Conn
+	remote, sdr.Conn = net.Pipe()
+	remote.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	msg := <-sdr.Pipe(ctx, NullParser{Chunk: 4})
+	assert.Equal(t, MessageError, msg.Kind)
+	assert.NotNil(t, msg.Err)
+}
+
+// discardCommands reads and drops the configuration commands Pipe sends via
+// SDR.Configure, standing in for the remote dongle in these tests.
+func discardCommands(conn net.Conn) {
+	buf := make([]byte, 512)
+	for {
+		if _, err := conn.Read(buf); err != nil {
+			return
+		}
+	}
+}
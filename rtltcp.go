@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/bemasher/rtltcp/si"
@@ -20,6 +21,11 @@ type SDR struct {
 	net.Conn
 	Config Config
 	Info   DongleInfo
+
+	// bufPool backs ReadAsync and Samples, lazily created on first use; all
+	// buffers in it are poolBufLen bytes long.
+	bufPool    *sync.Pool
+	poolBufLen int
 }
 
 // Give an address of the form "<hostname or IP>:<port>", connects to the spectrum
@@ -87,7 +93,10 @@ func (sdr SDR) Configure() (err error) {
 		case "TunerGainMode":
 			err = sdr.SetGainMode(sdr.Config.TunerGainMode)
 		case "TunerGain":
-			err = sdr.SetGain(uint32(sdr.Config.TunerGain * 10.0))
+			err = sdr.validateTunerGain()
+			if err == nil {
+				err = sdr.SetGain(uint32(sdr.Config.TunerGain * 10.0))
+			}
 		case "FreqCorrection":
 			err = sdr.SetFreqCorrection(uint32(sdr.Config.FreqCorrection))
 		case "TestMode":
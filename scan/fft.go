@@ -0,0 +1,45 @@
+package scan
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// fft computes the in-place iterative radix-2 Cooley-Tukey FFT of x. len(x)
+// must be a power of two; Scanner passes frames of ScanPlan.FFTSize samples
+// and does not check this.
+func fft(x []complex128) {
+	n := len(x)
+	if n <= 1 {
+		return
+	}
+
+	// Bit-reversal permutation.
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j &^= bit
+		}
+		j |= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		half := size / 2
+		angle := -2 * math.Pi / float64(size)
+		wn := cmplx.Exp(complex(0, angle))
+
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < half; k++ {
+				u := x[start+k]
+				v := x[start+k+half] * w
+				x[start+k] = u + v
+				x[start+k+half] = u - v
+				w *= wn
+			}
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Package scan implements a frequency-hopping spectrum scanner on top of an
+// rtltcp.SDR, roughly equivalent to rtl_power: it sweeps a configured
+// frequency plan and emits per-bin power estimates for each hop.
+package scan
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/cmplx"
+	"time"
+
+	"github.com/bemasher/rtltcp"
+)
+
+// Window selects the windowing function applied to each FFT frame.
+type Window int
+
+const (
+	Rect Window = iota
+	Hann
+)
+
+// ScanPlan describes a frequency sweep: the band to cover, how finely to
+// bin it, and the FFT parameters used to estimate power within each hop.
+type ScanPlan struct {
+	Start float64 // sweep start frequency in Hz
+	Stop  float64 // sweep stop frequency in Hz
+	BinHz float64 // width of a single output bin in Hz
+
+	DwellSamples int    // samples collected per hop
+	FFTSize      int    // FFT length; frames averaged per hop is DwellSamples/FFTSize
+	Window       Window // window function applied before each FFT
+
+	// SettleSamples is discarded after each SetCenterFreq to let the PLL
+	// settle before any sample is used for power estimation. Defaults to
+	// FFTSize*4 if zero.
+	SettleSamples int
+}
+
+// hopHz returns the span one FFT covers, FFTSize*BinHz, which Run also sets
+// as the dongle's sample rate. Run steps between hops by half of it.
+func (p ScanPlan) hopHz() float64 {
+	return float64(p.FFTSize) * p.BinHz
+}
+
+// ScanResult is one hop's power estimate, in dBFS: the middle half of that
+// hop's spectrum, centered on CenterFreq. Results of separate hops are not
+// merged into one.
+type ScanResult struct {
+	CenterFreq float64
+	Bins       []float32
+	Time       time.Time
+}
+
+// Scanner sweeps sdr across a ScanPlan and emits a ScanResult per hop.
+type Scanner struct {
+	sdr  *rtltcp.SDR
+	plan ScanPlan
+}
+
+// NewScanner returns a Scanner that will drive sdr through plan when Run is
+// called. It does not touch sdr until Run is called.
+func NewScanner(sdr *rtltcp.SDR, plan ScanPlan) *Scanner {
+	if plan.SettleSamples == 0 {
+		plan.SettleSamples = plan.FFTSize * 4
+	}
+	return &Scanner{sdr: sdr, plan: plan}
+}
+
+// Run configures sdr's sample rate for the plan and sweeps from Start to
+// Stop, sending a ScanResult per hop on the returned channel. Since dwell
+// only keeps the middle 50% of each hop's spectrum, hops step by half an
+// FFT's bandwidth so the retained regions cover the band with no gaps. The
+// channel is closed when the sweep finishes, ctx is done, or a read fails.
+func (sc *Scanner) Run(ctx context.Context) <-chan ScanResult {
+	out := make(chan ScanResult)
+
+	go func() {
+		defer close(out)
+
+		if err := sc.sdr.SetSampleRate(uint32(sc.plan.hopHz())); err != nil {
+			return
+		}
+
+		window := makeWindow(sc.plan.Window, sc.plan.FFTSize)
+		bufLen := sc.plan.FFTSize * 2 // 2 bytes (I, Q) per complex sample
+
+		for freq := sc.plan.Start; freq <= sc.plan.Stop; freq += sc.plan.hopHz() / 2 {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err := sc.sdr.SetCenterFreq(uint32(freq)); err != nil {
+				return
+			}
+			if err := sc.drain(sc.plan.SettleSamples * 2); err != nil {
+				return
+			}
+
+			bins, err := sc.dwell(window, bufLen)
+			if err != nil {
+				return
+			}
+
+			select {
+			case out <- ScanResult{CenterFreq: freq, Bins: bins, Time: time.Now()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// drain reads and discards n bytes of IQ samples, used to let the PLL
+// settle after retuning before any sample is used for power estimation.
+func (sc *Scanner) drain(n int) error {
+	buf := make([]byte, n)
+	_, err := io.ReadFull(sc.sdr, buf)
+	return err
+}
+
+// dwell reads enough samples to cover DwellSamples, runs a windowed FFT
+// over each FFTSize frame, and averages the resulting power spectra. The
+// averaged spectrum is then fftshifted so DC (CenterFreq) sits in the
+// middle of the array instead of at bin 0, and the outer 25% on each side
+// is dropped to avoid seams from filter rolloff when hops are stitched
+// together; what remains is centered on CenterFreq.
+func (sc *Scanner) dwell(window []float64, bufLen int) ([]float32, error) {
+	frames := sc.plan.DwellSamples / sc.plan.FFTSize
+	if frames < 1 {
+		frames = 1
+	}
+
+	avg := make([]float64, sc.plan.FFTSize)
+	buf := make([]byte, bufLen)
+
+	for i := 0; i < frames; i++ {
+		if _, err := io.ReadFull(sc.sdr, buf); err != nil {
+			return nil, err
+		}
+
+		frame := iqToComplex(buf)
+		for j, w := range window {
+			frame[j] *= complex(w, 0)
+		}
+		fft(frame)
+
+		for j, c := range frame {
+			avg[j] += cmplx.Abs(c) * cmplx.Abs(c)
+		}
+	}
+
+	// Normalize by the window's coherent gain squared so a full-scale tone
+	// reads 0 dBFS.
+	var wsum float64
+	for _, w := range window {
+		wsum += w
+	}
+
+	bins := make([]float32, sc.plan.FFTSize)
+	for j, p := range avg {
+		p /= float64(frames) * wsum * wsum
+		bins[j] = float32(10 * math.Log10(p+1e-20))
+	}
+	fftshift(bins)
+
+	trim := sc.plan.FFTSize / 4
+	return bins[trim : sc.plan.FFTSize-trim], nil
+}
+
+// fftshift swaps the first and second halves of x in place, moving the FFT
+// output's DC bin (at index 0) to the middle of the array so that
+// increasing index corresponds to increasing frequency relative to
+// CenterFreq.
+func fftshift(x []float32) {
+	mid := len(x) / 2
+	for i := 0; i < mid; i++ {
+		x[i], x[i+mid] = x[i+mid], x[i]
+	}
+}
+
+// iqToComplex converts a buffer of unsigned 8-bit interleaved I/Q samples to
+// zero-centered complex128 samples in [-1, 1].
+func iqToComplex(buf []byte) []complex128 {
+	out := make([]complex128, len(buf)/2)
+	for i := range out {
+		i8 := (float64(buf[2*i]) - 127.5) / 127.5
+		q8 := (float64(buf[2*i+1]) - 127.5) / 127.5
+		out[i] = complex(i8, q8)
+	}
+	return out
+}
+
+// makeWindow returns the window function's coefficients for an n-sample
+// frame.
+func makeWindow(w Window, n int) []float64 {
+	coeffs := make([]float64, n)
+	switch w {
+	case Hann:
+		for i := range coeffs {
+			coeffs[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(n-1)))
+		}
+	default:
+		for i := range coeffs {
+			coeffs[i] = 1
+		}
+	}
+	return coeffs
+}
@@ -0,0 +1,82 @@
+package scan
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFFTImpulse(t *testing.T) {
+	x := make([]complex128, 8)
+	x[0] = complex(1, 0)
+
+	fft(x)
+
+	for _, c := range x {
+		assert.InDelta(t, 1.0, real(c), 1e-9)
+		assert.InDelta(t, 0.0, imag(c), 1e-9)
+	}
+}
+
+func TestFFTTone(t *testing.T) {
+	const n = 8
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(math.Cos(2*math.Pi*float64(i)/n), math.Sin(2*math.Pi*float64(i)/n))
+	}
+
+	fft(x)
+
+	for i, c := range x {
+		mag := math.Hypot(real(c), imag(c))
+		if i == 1 {
+			assert.InDelta(t, float64(n), mag, 1e-9)
+		} else {
+			assert.InDelta(t, 0.0, mag, 1e-9)
+		}
+	}
+}
+
+func TestFFTShift(t *testing.T) {
+	x := []float32{0, 1, 2, 3, 4, 5, 6, 7}
+	fftshift(x)
+	assert.Equal(t, []float32{4, 5, 6, 7, 0, 1, 2, 3}, x)
+}
+
+func TestDwellCentersDC(t *testing.T) {
+	// A pure DC tone's FFT has all its energy in bin 0; after fftshift that
+	// bin sits in the middle of the array, so the trimmed, kept region
+	// (which straddles the middle) should contain the peak.
+	const n = 8
+	frame := make([]complex128, n)
+	for i := range frame {
+		frame[i] = complex(1, 0)
+	}
+	fft(frame)
+
+	bins := make([]float32, n)
+	for i, c := range frame {
+		bins[i] = float32(cmplx.Abs(c))
+	}
+	fftshift(bins)
+
+	peak := 0
+	for i, v := range bins {
+		if v > bins[peak] {
+			peak = i
+		}
+	}
+	assert.Equal(t, n/2, peak)
+}
+
+func TestMakeWindow(t *testing.T) {
+	rect := makeWindow(Rect, 4)
+	assert.Equal(t, []float64{1, 1, 1, 1}, rect)
+
+	hann := makeWindow(Hann, 5)
+	assert.InDelta(t, 0.0, hann[0], 1e-9)
+	assert.InDelta(t, 0.0, hann[len(hann)-1], 1e-9)
+	assert.InDelta(t, 1.0, hann[len(hann)/2], 1e-9)
+}
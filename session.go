@@ -0,0 +1,209 @@
+package rtltcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DongleStats is a point-in-time snapshot of a single dongle's throughput,
+// returned by Session.Stats.
+type DongleStats struct {
+	BytesPerSec    float64
+	DroppedBuffers uint64
+}
+
+// dongle holds the per-connection state owned by a Session: its dial target
+// and configuration, the live SDR once connected, the channel its samples
+// are delivered on, and the running counters behind Stats.
+type dongle struct {
+	name    string
+	address string
+	config  Config
+	timeout time.Duration
+
+	samples chan []byte
+
+	started time.Time
+	bytes   uint64
+	dropped uint64
+
+	// backoffInitial/backoffMax bound run's reconnect backoff; they're
+	// fields (rather than constants) so tests can shrink them. Defaulted
+	// in Add.
+	backoffInitial time.Duration
+	backoffMax     time.Duration
+}
+
+// Session owns a set of named SDR connections and runs them concurrently,
+// modeled on pairing one dongle on 978MHz (UAT) with another on 1090MHz (ES).
+// A Session must not be copied after first use.
+type Session struct {
+	mu      sync.Mutex
+	dongles map[string]*dongle
+}
+
+// NewSession returns an empty Session ready to have dongles Added to it.
+func NewSession() *Session {
+	return &Session{dongles: make(map[string]*dongle)}
+}
+
+// Add registers a dongle by name with the address and Config it should be
+// connected and configured with when Run is called. Add must be called
+// before Run; it panics if name is already registered.
+func (s *Session) Add(name, address string, config Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.dongles[name]; ok {
+		panic(fmt.Sprintf("rtltcp: dongle %q already added", name))
+	}
+
+	s.dongles[name] = &dongle{
+		name:           name,
+		address:        address,
+		config:         config,
+		timeout:        5 * time.Second,
+		samples:        make(chan []byte, 8),
+		started:        time.Now(),
+		backoffInitial: 500 * time.Millisecond,
+		backoffMax:     30 * time.Second,
+	}
+}
+
+// Samples returns the channel that name's samples are delivered on once Run
+// is streaming, or nil if name was never Added.
+func (s *Session) Samples(name string) <-chan []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.dongles[name]
+	if !ok {
+		return nil
+	}
+	return d.samples
+}
+
+// Stats returns a snapshot of bytes/sec and dropped-buffer counts for every
+// dongle Added to the Session, keyed by name.
+func (s *Session) Stats() map[string]DongleStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make(map[string]DongleStats, len(s.dongles))
+	for name, d := range s.dongles {
+		elapsed := time.Since(d.started).Seconds()
+		if elapsed <= 0 {
+			elapsed = 1
+		}
+
+		stats[name] = DongleStats{
+			BytesPerSec:    float64(atomic.LoadUint64(&d.bytes)) / elapsed,
+			DroppedBuffers: atomic.LoadUint64(&d.dropped),
+		}
+	}
+	return stats
+}
+
+// Run dials and configures every dongle Added to the Session and streams
+// samples from each until ctx is done or one of them fails permanently, in
+// which case Run cancels the rest and returns that dongle's error. Run
+// reconnects a dongle whose connection drops, backing off exponentially
+// between attempts, and blocks until every dongle's goroutine has exited.
+func (s *Session) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(s.dongles))
+
+	for _, d := range s.dongles {
+		wg.Add(1)
+		go func(d *dongle) {
+			defer wg.Done()
+			if err := d.run(ctx); err != nil && ctx.Err() == nil {
+				errCh <- fmt.Errorf("dongle %q: %w", d.name, err)
+				cancel()
+			}
+		}(d)
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	if err, ok := <-errCh; ok {
+		return err
+	}
+	return ctx.Err()
+}
+
+// run dials d, reconnecting with exponential backoff whenever the connection
+// is lost or the stream drops, and streams samples to d.samples until ctx is
+// done. backoff is only reset once a session has stayed up longer than
+// backoffMax, mirroring the heuristic LocalServer.supervise uses for its own
+// subprocess backoff; otherwise, once the backoff has grown to backoffMax
+// and an attempt still fails, the error is treated as permanent and returned
+// so Run can tear down the rest of the Session.
+func (d *dongle) run(ctx context.Context) error {
+	backoff := d.backoffInitial
+	for {
+		start := time.Now()
+		err := d.connectAndStream(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if time.Since(start) > d.backoffMax {
+			backoff = d.backoffInitial
+		} else if backoff >= d.backoffMax {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil
+		}
+
+		backoff *= 2
+		if backoff > d.backoffMax {
+			backoff = d.backoffMax
+		}
+	}
+}
+
+// connectAndStream dials d, configures it, and copies samples into
+// d.samples, incrementing d.bytes as they arrive and d.dropped whenever a
+// buffer can't be delivered because nothing is receiving from d.samples. It
+// returns ctx.Err() (always non-nil) if ctx is what ended the stream, and
+// otherwise a non-nil error describing why the stream ended — a dropped
+// connection is never reported as success, so run always backs off before
+// redialing.
+func (d *dongle) connectAndStream(ctx context.Context) error {
+	var sdr SDR
+	if err := sdr.Connect(d.address, d.timeout); err != nil {
+		return fmt.Errorf("error connecting: %w", err)
+	}
+	defer sdr.Close()
+
+	sdr.Config = d.config
+	if err := sdr.Configure(); err != nil {
+		return fmt.Errorf("error configuring: %w", err)
+	}
+
+	const bufLen = 16384
+	return sdr.ReadAsync(ctx, bufLen, 0, func(buf []byte) {
+		atomic.AddUint64(&d.bytes, uint64(len(buf)))
+
+		out := make([]byte, len(buf))
+		copy(out, buf)
+
+		select {
+		case d.samples <- out:
+		default:
+			atomic.AddUint64(&d.dropped, 1)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package rtltcp
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDongle starts a one-shot listener that behaves enough like rtl_tcp to
+// exercise Session.Run: it writes a DongleInfo header then streams zeroed
+// sample buffers until the connection is closed.
+func fakeDongle(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		di := DongleInfo{Magic: dongleMagic, Tuner: 5, GainCount: 29}
+		if err := binary.Write(conn, binary.BigEndian, di); err != nil {
+			return
+		}
+
+		// Drain the configuration commands rtl_tcp would otherwise consume.
+		go io_discard(conn)
+
+		buf := make([]byte, 16384)
+		for {
+			if _, err := conn.Write(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func io_discard(conn net.Conn) {
+	buf := make([]byte, 512)
+	for {
+		if _, err := conn.Read(buf); err != nil {
+			return
+		}
+	}
+}
+
+// droppingDongle starts a listener that behaves like rtl_tcp just long
+// enough to send its header, then immediately closes the connection,
+// simulating a stream that drops right after connecting (device busy, cable
+// flap) rather than a failed dial.
+func droppingDongle(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			di := DongleInfo{Magic: dongleMagic, Tuner: 5, GainCount: 29}
+			binary.Write(conn, binary.BigEndian, di)
+			conn.Close()
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestSessionBacksOffOnStreamDrop(t *testing.T) {
+	s := NewSession()
+	s.Add("dropper", droppingDongle(t), DefaultConfig())
+
+	d := s.dongles["dropper"]
+	d.backoffInitial = 20 * time.Millisecond
+	d.backoffMax = 80 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := s.Run(ctx)
+	elapsed := time.Since(start)
+
+	if !assert.NotNil(t, err) {
+		t.FailNow()
+	}
+	assert.Contains(t, err.Error(), "dropper")
+
+	// A stream drop must be treated the same as a dial failure: run should
+	// sleep at least one backoffInitial before redialing, rather than
+	// spinning immediately and returning almost instantly.
+	assert.GreaterOrEqual(t, elapsed, d.backoffInitial)
+}
+
+func TestSessionTearsDownOnPermanentFailure(t *testing.T) {
+	s := NewSession()
+	s.Add("bad", "127.0.0.1:1", DefaultConfig()) // nothing listens here
+
+	d := s.dongles["bad"]
+	d.backoffInitial = time.Millisecond
+	d.backoffMax = 4 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.Run(ctx)
+	if !assert.NotNil(t, err) {
+		t.FailNow()
+	}
+	assert.Contains(t, err.Error(), "bad")
+}
+
+func TestSessionMultiDongle(t *testing.T) {
+	s := NewSession()
+	s.Add("uat", fakeDongle(t), DefaultConfig())
+	s.Add("es", fakeDongle(t), DefaultConfig())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	uatSamples, esSamples := 0, 0
+	for uatSamples == 0 || esSamples == 0 {
+		select {
+		case buf := <-s.Samples("uat"):
+			if buf != nil {
+				uatSamples++
+			}
+		case buf := <-s.Samples("es"):
+			if buf != nil {
+				esSamples++
+			}
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for samples from both dongles")
+		}
+	}
+
+	<-done
+
+	stats := s.Stats()
+	assert.Contains(t, stats, "uat")
+	assert.Contains(t, stats, "es")
+}